@@ -0,0 +1,381 @@
+package file_extractor
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bodgit/sevenzip"
+)
+
+// archiveMIMETypes are the container formats ExtractArchive knows how to
+// walk into.
+var archiveMIMETypes = map[string]bool{
+	"application/zip":             true,
+	"application/x-tar":           true,
+	"application/gzip":            true,
+	"application/x-bzip2":         true,
+	"application/x-7z-compressed": true,
+}
+
+// ArchiveLimits bounds the work ExtractArchive does when walking into a
+// container, guarding against zip bombs and unbounded nested-archive
+// recursion.
+type ArchiveLimits struct {
+	MaxDepth     int   // how many nested archives to recurse into
+	MaxEntrySize int64 // bytes read from any single member
+	MaxTotalSize int64 // aggregate bytes read across all members
+}
+
+// DefaultArchiveLimits are the limits ExtractArchive uses when nil is
+// passed in their place.
+var DefaultArchiveLimits = ArchiveLimits{
+	MaxDepth:     3,
+	MaxEntrySize: 100 << 20, // 100MB
+	MaxTotalSize: 500 << 20, // 500MB
+}
+
+// Entry is the outcome of extracting one member of an archive.
+type Entry struct {
+	Path string
+	MIME string
+	Text string
+	Err  error
+}
+
+// ArchiveResult is the per-member outcome of walking an archive's contents.
+type ArchiveResult struct {
+	Entries []Entry
+}
+
+// archiveWalkState carries the limits and running totals for one
+// ExtractArchive call across its (possibly recursive) archive walk.
+type archiveWalkState struct {
+	limits    ArchiveLimits
+	totalRead int64
+}
+
+// ExtractArchive walks filePath as an archive (zip, tar, tar.gz, tar.bz2, or
+// 7z), recursively re-invoking ExtractText on each member, and returns the
+// per-member outcome. Pass nil for limits to use DefaultArchiveLimits.
+func ExtractArchive(filePath string, limits *ArchiveLimits) (*ArchiveResult, error) {
+	effectiveLimits := DefaultArchiveLimits
+	if limits != nil {
+		effectiveLimits = *limits
+	}
+
+	state := &archiveWalkState{limits: effectiveLimits}
+	entries, err := state.walk(filePath, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ArchiveResult{Entries: entries}, nil
+}
+
+// extractArchiveText flattens ExtractArchive's per-member results into
+// ExtractText's plain (success, text, error) shape, concatenating each
+// member's text behind a path-prefixed header.
+func extractArchiveText(filePath string) (bool, string, error) {
+	result, err := ExtractArchive(filePath, nil)
+	if err != nil {
+		return false, "", err
+	}
+
+	var out strings.Builder
+	for _, entry := range result.Entries {
+		if entry.Err != nil || strings.TrimSpace(entry.Text) == "" {
+			continue
+		}
+		fmt.Fprintf(&out, "=== %s ===\n", entry.Path)
+		out.WriteString(entry.Text)
+		out.WriteString("\n")
+	}
+
+	text := out.String()
+	if len(strings.TrimSpace(text)) == 0 {
+		return false, "", nil
+	}
+	return true, text, nil
+}
+
+// walk dispatches filePath to the right container walker based on its
+// sniffed MIME type.
+func (s *archiveWalkState) walk(filePath string, depth int) ([]Entry, error) {
+	if depth > s.limits.MaxDepth {
+		return []Entry{{Path: filePath, Err: fmt.Errorf("archive recursion depth limit (%d) exceeded", s.limits.MaxDepth)}}, nil
+	}
+
+	mime, err := DetectMIME(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	switch mime {
+	case "application/zip":
+		return s.walkZip(filePath, depth)
+	case "application/x-tar":
+		return s.walkTarFile(filePath, depth)
+	case "application/gzip":
+		return s.walkGzip(filePath, depth)
+	case "application/x-bzip2":
+		return s.walkBzip2(filePath, depth)
+	case "application/x-7z-compressed":
+		return s.walk7z(filePath, depth)
+	}
+
+	return nil, fmt.Errorf("%s is not a recognized archive format", filePath)
+}
+
+func (s *archiveWalkState) walkZip(filePath string, depth int) ([]Entry, error) {
+	r, closeZip, err := openZipReader(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer closeZip()
+
+	var entries []Entry
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if !isSafeArchiveMemberName(f.Name) {
+			entries = append(entries, Entry{Path: f.Name, Err: fmt.Errorf("unsafe archive member path")})
+			continue
+		}
+		if f.Mode()&os.ModeSymlink != 0 {
+			entries = append(entries, Entry{Path: f.Name, Err: fmt.Errorf("symlink members are not extracted")})
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			entries = append(entries, Entry{Path: f.Name, Err: err})
+			continue
+		}
+		entries = append(entries, s.extractMember(rc, f.Name, depth)...)
+		rc.Close()
+	}
+
+	return entries, nil
+}
+
+func (s *archiveWalkState) walk7z(filePath string, depth int) ([]Entry, error) {
+	r, err := sevenzip.OpenReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read 7z archive %s: %v", filePath, err)
+	}
+	defer r.Close()
+
+	var entries []Entry
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if !isSafeArchiveMemberName(f.Name) {
+			entries = append(entries, Entry{Path: f.Name, Err: fmt.Errorf("unsafe archive member path")})
+			continue
+		}
+		if f.Mode()&os.ModeSymlink != 0 {
+			entries = append(entries, Entry{Path: f.Name, Err: fmt.Errorf("symlink members are not extracted")})
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			entries = append(entries, Entry{Path: f.Name, Err: err})
+			continue
+		}
+		entries = append(entries, s.extractMember(rc, f.Name, depth)...)
+		rc.Close()
+	}
+
+	return entries, nil
+}
+
+func (s *archiveWalkState) walkTarFile(filePath string, depth int) ([]Entry, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %v", filePath, err)
+	}
+	defer file.Close()
+
+	return s.walkTarReader(file, depth)
+}
+
+func (s *archiveWalkState) walkTarReader(r io.Reader, depth int) ([]Entry, error) {
+	tr := tar.NewReader(r)
+
+	var entries []Entry
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar entry: %v", err)
+		}
+
+		if hdr.Typeflag == tar.TypeDir {
+			continue
+		}
+		if !isSafeArchiveMemberName(hdr.Name) {
+			entries = append(entries, Entry{Path: hdr.Name, Err: fmt.Errorf("unsafe archive member path")})
+			continue
+		}
+		if hdr.Typeflag == tar.TypeSymlink || hdr.Typeflag == tar.TypeLink {
+			entries = append(entries, Entry{Path: hdr.Name, Err: fmt.Errorf("symlink members are not extracted")})
+			continue
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		entries = append(entries, s.extractMember(tr, hdr.Name, depth)...)
+	}
+
+	return entries, nil
+}
+
+// isTarStream reports whether br's next 262 bytes carry the "ustar" magic
+// at its tar header offset, without consuming them.
+func isTarStream(br *bufio.Reader) bool {
+	sample, _ := br.Peek(262)
+	return len(sample) >= 262 && string(sample[257:262]) == "ustar"
+}
+
+func (s *archiveWalkState) walkGzip(filePath string, depth int) ([]Entry, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %v", filePath, err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gzip stream %s: %v", filePath, err)
+	}
+	defer gz.Close()
+
+	br := bufio.NewReader(gz)
+	if isTarStream(br) {
+		return s.walkTarReader(br, depth)
+	}
+
+	name := gz.Name
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	}
+	return s.extractMember(br, name, depth), nil
+}
+
+func (s *archiveWalkState) walkBzip2(filePath string, depth int) ([]Entry, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %v", filePath, err)
+	}
+	defer file.Close()
+
+	br := bufio.NewReader(bzip2.NewReader(file))
+	if isTarStream(br) {
+		return s.walkTarReader(br, depth)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	return s.extractMember(br, name, depth), nil
+}
+
+// extractMember spools one archive member to a temp file and either
+// recurses into it (if it's itself an archive, within MaxDepth) or runs it
+// through ExtractText, returning the resulting Entry (or entries, for a
+// nested archive, with memberName prefixed onto each inner path). A nested
+// archive past MaxDepth is returned as a single Entry with no text.
+func (s *archiveWalkState) extractMember(r io.Reader, memberName string, depth int) []Entry {
+	tmpPath, err := s.spool(r)
+	if err != nil {
+		return []Entry{{Path: memberName, Err: err}}
+	}
+	defer os.Remove(tmpPath)
+
+	mime, err := DetectMIME(tmpPath)
+	if err != nil {
+		return []Entry{{Path: memberName, Err: err}}
+	}
+
+	if archiveMIMETypes[mime] && depth < s.limits.MaxDepth {
+		nested, err := s.walk(tmpPath, depth+1)
+		if err != nil {
+			return []Entry{{Path: memberName, MIME: mime, Err: err}}
+		}
+		for i := range nested {
+			nested[i].Path = path.Join(memberName, nested[i].Path)
+		}
+		return nested
+	}
+	if archiveMIMETypes[mime] {
+		return []Entry{{Path: memberName, MIME: mime}}
+	}
+
+	success, text, err := ExtractText(tmpPath)
+	entry := Entry{Path: memberName, MIME: mime}
+	if err != nil {
+		entry.Err = err
+	} else if success {
+		entry.Text = text
+	}
+	return []Entry{entry}
+}
+
+// spool copies r to a temp file, bounded by the per-entry and aggregate
+// size limits still remaining on s.
+func (s *archiveWalkState) spool(r io.Reader) (string, error) {
+	if s.totalRead >= s.limits.MaxTotalSize {
+		return "", fmt.Errorf("aggregate archive size limit (%d bytes) exceeded", s.limits.MaxTotalSize)
+	}
+
+	tmp, err := os.CreateTemp("", "file-extractor-archive-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %v", err)
+	}
+	defer tmp.Close()
+
+	limit := s.limits.MaxEntrySize
+	if remaining := s.limits.MaxTotalSize - s.totalRead; remaining < limit {
+		limit = remaining
+	}
+
+	n, err := io.Copy(tmp, io.LimitReader(r, limit+1))
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to read archive member: %v", err)
+	}
+	if n > limit {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("archive member exceeds size limit (%d bytes)", limit)
+	}
+
+	s.totalRead += n
+	return tmp.Name(), nil
+}
+
+// isSafeArchiveMemberName rejects absolute paths and ".." traversal
+// segments in an archive member's name.
+func isSafeArchiveMemberName(name string) bool {
+	if name == "" || path.IsAbs(name) || strings.Contains(name, "\\") {
+		return false
+	}
+
+	cleaned := path.Clean(name)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,187 @@
+package file_extractor
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsSafeArchiveMemberName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{"notes.txt", true},
+		{"dir/notes.txt", true},
+		{"../escape.txt", false},
+		{"dir/../../escape.txt", false},
+		{"/etc/passwd", false},
+		{`C:\windows\system32`, false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSafeArchiveMemberName(tt.name); got != tt.expected {
+				t.Errorf("for %q: expected %v, got %v", tt.name, tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsTarStream(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{Name: "a.txt", Size: 5, Mode: 0644}); err != nil {
+		t.Fatalf("failed to write tar header: %v", err)
+	}
+	if _, err := tw.Write([]byte("hello")); err != nil {
+		t.Fatalf("failed to write tar content: %v", err)
+	}
+	tw.Close()
+
+	if !isTarStream(bufio.NewReader(bytes.NewReader(buf.Bytes()))) {
+		t.Error("expected a tar stream to be recognized")
+	}
+	if isTarStream(bufio.NewReader(bytes.NewReader([]byte("not a tar stream at all, just plain text")))) {
+		t.Error("expected plain text to not be recognized as a tar stream")
+	}
+}
+
+func writeTestZip(t *testing.T, dir string, entries map[string]string) string {
+	t.Helper()
+
+	zipPath := filepath.Join(dir, "test.zip")
+	f, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatalf("failed to create zip file: %v", err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for name, content := range entries {
+		entry, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("failed to create zip entry %s: %v", name, err)
+		}
+		if _, err := entry.Write([]byte(content)); err != nil {
+			t.Fatalf("failed to write zip entry %s: %v", name, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close zip writer: %v", err)
+	}
+
+	return zipPath
+}
+
+func TestExtractArchive_Zip(t *testing.T) {
+	dir := t.TempDir()
+	zipPath := writeTestZip(t, dir, map[string]string{
+		"notes.txt":      "hello from a zip member",
+		"../escape.txt":  "should be rejected",
+		"sub/deeper.txt": "nested directory entry",
+	})
+
+	result, err := ExtractArchive(zipPath, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var sawNotes, sawEscapeRejected, sawNested bool
+	for _, entry := range result.Entries {
+		switch entry.Path {
+		case "notes.txt":
+			sawNotes = entry.Text == "hello from a zip member"
+		case "../escape.txt":
+			sawEscapeRejected = entry.Err != nil
+		case "sub/deeper.txt":
+			sawNested = entry.Text == "nested directory entry"
+		}
+	}
+
+	if !sawNotes {
+		t.Error("expected notes.txt to be extracted with its content")
+	}
+	if !sawEscapeRejected {
+		t.Error("expected the path-traversal member to be rejected with an error")
+	}
+	if !sawNested {
+		t.Error("expected sub/deeper.txt to be extracted with its content")
+	}
+}
+
+func TestExtractArchive_TarGz(t *testing.T) {
+	dir := t.TempDir()
+	tgzPath := filepath.Join(dir, "test.tar.gz")
+
+	f, err := os.Create(tgzPath)
+	if err != nil {
+		t.Fatalf("failed to create tar.gz file: %v", err)
+	}
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	content := "hello from a tar.gz member"
+	if err := tw.WriteHeader(&tar.Header{Name: "notes.txt", Size: int64(len(content)), Mode: 0644}); err != nil {
+		t.Fatalf("failed to write tar header: %v", err)
+	}
+	if _, err := tw.Write([]byte(content)); err != nil {
+		t.Fatalf("failed to write tar content: %v", err)
+	}
+	tw.Close()
+	gw.Close()
+	f.Close()
+
+	result, err := ExtractArchive(tgzPath, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Entries) != 1 || result.Entries[0].Text != content {
+		t.Errorf("expected a single notes.txt entry with %q, got %+v", content, result.Entries)
+	}
+}
+
+func TestExtractArchive_MaxDepth(t *testing.T) {
+	dir := t.TempDir()
+	inner := writeTestZip(t, dir, map[string]string{"notes.txt": "hi"})
+	innerData, err := os.ReadFile(inner)
+	if err != nil {
+		t.Fatalf("failed to read inner zip: %v", err)
+	}
+
+	outerPath := writeTestZip(t, dir, map[string]string{"inner.zip": string(innerData)})
+
+	limits := &ArchiveLimits{MaxDepth: 0, MaxEntrySize: DefaultArchiveLimits.MaxEntrySize, MaxTotalSize: DefaultArchiveLimits.MaxTotalSize}
+	result, err := ExtractArchive(outerPath, limits)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Entries) != 1 || result.Entries[0].Err != nil {
+		t.Fatalf("expected the nested zip to be left unexpanded at depth 0, got %+v", result.Entries)
+	}
+}
+
+func TestExtractText_ZipArchive(t *testing.T) {
+	dir := t.TempDir()
+	zipPath := writeTestZip(t, dir, map[string]string{"notes.txt": "archived text content"})
+
+	success, text, err := ExtractText(zipPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !success {
+		t.Fatal("expected ExtractText to succeed on a zip archive")
+	}
+	if !bytes.Contains([]byte(text), []byte("archived text content")) {
+		t.Errorf("expected output to contain the member's text, got %q", text)
+	}
+}
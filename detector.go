@@ -0,0 +1,183 @@
+package file_extractor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Detector identifies the MIME type of a file from a sample of its content.
+// It lets callers plug in their own sniffing strategy (e.g. a fuller
+// magic-byte database) in place of DefaultDetector.
+type Detector interface {
+	Detect(sample []byte) string
+}
+
+// DefaultDetector sniffs MIME types from magic byte signatures, covering
+// common archive, image, and document formats, and falls back to
+// net/http's content-type detection for anything it doesn't recognize.
+type DefaultDetector struct{}
+
+// activeDetector is the Detector used by DetectMIME and the rest of the
+// package. Callers can swap it out via SetDetector to plug in a richer
+// implementation (e.g. one backed by gabriel-vasile/mimetype).
+var activeDetector Detector = DefaultDetector{}
+
+// SetDetector replaces the package-wide Detector used for MIME sniffing.
+// Passing nil restores DefaultDetector.
+func SetDetector(d Detector) {
+	if d == nil {
+		d = DefaultDetector{}
+	}
+	activeDetector = d
+}
+
+// magicSignature pairs a byte pattern at a given offset with the MIME type
+// it identifies.
+type magicSignature struct {
+	mime   string
+	offset int
+	magic  []byte
+}
+
+// magicSignatures is checked in order; the first match wins. It covers the
+// formats this package knows how to extract from or recurse into, plus a
+// handful of common binary types useful for extension/content cross-checks.
+var magicSignatures = []magicSignature{
+	{"application/pdf", 0, []byte("%PDF-")},
+	{"application/zip", 0, []byte{0x50, 0x4B, 0x03, 0x04}},
+	{"application/zip", 0, []byte{0x50, 0x4B, 0x05, 0x06}},
+	{"application/gzip", 0, []byte{0x1F, 0x8B}},
+	{"application/x-bzip2", 0, []byte("BZh")},
+	{"application/x-tar", 257, []byte("ustar")},
+	{"application/x-7z-compressed", 0, []byte{0x37, 0x7A, 0xBC, 0xAF, 0x27, 0x1C}},
+	{"image/png", 0, []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}},
+	{"image/jpeg", 0, []byte{0xFF, 0xD8, 0xFF}},
+	{"image/gif", 0, []byte("GIF87a")},
+	{"image/gif", 0, []byte("GIF89a")},
+	{"image/bmp", 0, []byte("BM")},
+	{"image/tiff", 0, []byte{0x49, 0x49, 0x2A, 0x00}},
+	{"image/tiff", 0, []byte{0x4D, 0x4D, 0x00, 0x2A}},
+}
+
+// Detect implements Detector using the magic-byte table above, falling back
+// to http.DetectContentType for anything that doesn't match.
+func (DefaultDetector) Detect(sample []byte) string {
+	for _, sig := range magicSignatures {
+		if len(sample) < sig.offset+len(sig.magic) {
+			continue
+		}
+		if bytes.Equal(sample[sig.offset:sig.offset+len(sig.magic)], sig.magic) {
+			return sig.mime
+		}
+	}
+	return http.DetectContentType(sample)
+}
+
+// sniffSampleSize is the number of leading bytes read for MIME sniffing. It
+// needs to cover the largest signature offset in magicSignatures (the tar
+// "ustar" magic at offset 257) plus its length.
+const sniffSampleSize = 512
+
+// DetectMIME reports the MIME type of the file at filePath, sniffed from its
+// content via the active Detector.
+func DetectMIME(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file %s: %v", filePath, err)
+	}
+	defer file.Close()
+
+	buffer := make([]byte, sniffSampleSize)
+	n, err := file.Read(buffer)
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read file sample: %v", err)
+	}
+
+	return activeDetector.Detect(buffer[:n]), nil
+}
+
+// mimeFamily classifies a MIME type into a coarse family ("text", "image",
+// "archive", "binary", ...) for extension/content cross-checking.
+func mimeFamily(mimeType string) string {
+	mainType := strings.Split(mimeType, ";")[0]
+	mainType = strings.TrimSpace(strings.ToLower(mainType))
+
+	switch {
+	case strings.HasPrefix(mainType, "text/"):
+		return "text"
+	case strings.HasPrefix(mainType, "image/"):
+		return "image"
+	case strings.HasPrefix(mainType, "audio/"):
+		return "audio"
+	case strings.HasPrefix(mainType, "video/"):
+		return "video"
+	}
+
+	switch mainType {
+	case "application/json", "application/xml", "application/javascript",
+		"application/x-sh", "application/x-python", "application/x-perl",
+		"application/x-ruby", "application/x-php", "application/sql",
+		"application/yaml", "application/x-yaml":
+		return "text"
+	case "application/zip", "application/gzip", "application/x-bzip2",
+		"application/x-tar", "application/x-7z-compressed":
+		return "archive"
+	case "application/pdf":
+		return "pdf"
+	}
+
+	return "binary"
+}
+
+// MismatchError reports that a file's extension and its sniffed content
+// disagree about what kind of file it is (e.g. a ".txt" that is actually a
+// PNG).
+type MismatchError struct {
+	FilePath string
+	Declared string // MIME family implied by the file extension
+	Detected string // MIME type found by sniffing the content
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("%s: extension implies %q but content sniffs as %q", e.FilePath, e.Declared, e.Detected)
+}
+
+// checkExtensionContentMatch cross-checks the family implied by filePath's
+// extension against the family of its sniffed content, following the
+// extension/content cross-validation approach used by tools like Hugo's
+// content-type guard. It returns a *MismatchError when the extension implies
+// text but the content sniffs as an image, audio, video, archive, or PDF; a
+// non-text extension on text content is not flagged. Files that can't be
+// opened are left for the caller's own read to report, so this never masks
+// the real error.
+func checkExtensionContentMatch(filePath string) error {
+	sniffedMIME, err := DetectMIME(filePath)
+	if err != nil {
+		return nil
+	}
+
+	return textExtensionMismatch(filePath, sniffedMIME)
+}
+
+// textExtensionMismatch is the cross-check checkExtensionContentMatch and
+// the streaming path (extractPlainTextReaderTo) both share, so a renamed
+// PNG/archive/etc. is caught the same way whether the file is read by path
+// or by io.Reader. It returns a *MismatchError if filePath's extension
+// implies text but mimeType's family doesn't, or nil if filePath isn't
+// claiming to be text or the families agree.
+func textExtensionMismatch(filePath string, mimeType string) error {
+	if !isTextByExtension(filePath) {
+		return nil
+	}
+
+	switch mimeFamily(mimeType) {
+	case "image", "audio", "video", "archive", "pdf":
+		return &MismatchError{FilePath: filePath, Declared: "text", Detected: mimeType}
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package file_extractor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDefaultDetector_Detect(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{"PDF magic", []byte("%PDF-1.4 rest of file"), "application/pdf"},
+		{"zip magic", []byte{0x50, 0x4B, 0x03, 0x04, 0x00, 0x00}, "application/zip"},
+		{"gzip magic", []byte{0x1F, 0x8B, 0x08, 0x00}, "application/gzip"},
+		{"bzip2 magic", []byte("BZh91AY&SY"), "application/x-bzip2"},
+		{"7z magic", []byte{0x37, 0x7A, 0xBC, 0xAF, 0x27, 0x1C}, "application/x-7z-compressed"},
+		{"png magic", []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}, "image/png"},
+		{"plain text falls back to http detection", []byte("hello world"), "text/plain; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DefaultDetector{}.Detect(tt.data)
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDefaultDetector_Detect_TarRequiresOffset(t *testing.T) {
+	sample := make([]byte, 512)
+	copy(sample[257:], []byte("ustar"))
+
+	got := DefaultDetector{}.Detect(sample)
+	if got != "application/x-tar" {
+		t.Errorf("expected application/x-tar, got %q", got)
+	}
+}
+
+func TestMimeFamily(t *testing.T) {
+	tests := []struct {
+		mimeType string
+		expected string
+	}{
+		{"text/plain; charset=utf-8", "text"},
+		{"application/json", "text"},
+		{"image/png", "image"},
+		{"audio/mpeg", "audio"},
+		{"video/mp4", "video"},
+		{"application/zip", "archive"},
+		{"application/pdf", "pdf"},
+		{"application/octet-stream", "binary"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mimeType, func(t *testing.T) {
+			got := mimeFamily(tt.mimeType)
+			if got != tt.expected {
+				t.Errorf("for %s: expected %q, got %q", tt.mimeType, tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCheckExtensionContentMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		content  []byte
+		wantErr  bool
+	}{
+		{"text extension with text content", "notes.txt", []byte("just plain text"), false},
+		{"text extension with PNG content", "notes.txt", []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}, true},
+		{"text extension with zip content", "notes.txt", []byte{0x50, 0x4B, 0x03, 0x04, 0x00, 0x00}, true},
+		{"non-text extension unaffected", "photo.png", []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}, false},
+	}
+
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(dir, tt.fileName)
+			if err := os.WriteFile(filePath, tt.content, 0644); err != nil {
+				t.Fatalf("failed to write test file: %v", err)
+			}
+			defer os.Remove(filePath)
+
+			err := checkExtensionContentMatch(filePath)
+			if tt.wantErr && err == nil {
+				t.Fatal("expected a MismatchError, got nil")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if tt.wantErr {
+				var mismatch *MismatchError
+				if !errors.As(err, &mismatch) {
+					t.Fatalf("expected *MismatchError, got %T", err)
+				}
+				if !strings.Contains(mismatch.Error(), filePath) {
+					t.Errorf("expected error message to mention %q, got %q", filePath, mismatch.Error())
+				}
+			}
+		})
+	}
+}
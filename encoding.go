@@ -0,0 +1,137 @@
+package file_extractor
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/saintfish/chardet"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/encoding/unicode/utf32"
+)
+
+// minDetectionConfidence is the lowest statistical-detector confidence (out
+// of 100) this package trusts enough to transcode. Below it, content is
+// treated as undetected rather than risking a wrong transcoding.
+const minDetectionConfidence = 50
+
+// DetectedEncoding describes the character encoding ExtractTextDetailed
+// found in a file, and how confident it is in that finding. A BOM match is
+// always Confidence 100; anything from the statistical detector carries its
+// own confidence score.
+type DetectedEncoding struct {
+	Name       string // IANA-style charset name, e.g. "UTF-8", "Shift_JIS"
+	Confidence int    // 0-100
+}
+
+// utf8Encoding is the DetectedEncoding reported for content this package
+// already knows is UTF-8 (plain text files, and formats like Office/ODF and
+// archives whose extractors always hand back UTF-8 Go strings).
+var utf8Encoding = DetectedEncoding{Name: "UTF-8", Confidence: 100}
+
+// bomSignatures are checked longest-first so a UTF-32LE BOM isn't mistaken
+// for a UTF-16LE one (they share their first two bytes).
+var bomSignatures = []struct {
+	name string
+	bom  []byte
+}{
+	{"UTF-32BE", []byte{0x00, 0x00, 0xFE, 0xFF}},
+	{"UTF-32LE", []byte{0xFF, 0xFE, 0x00, 0x00}},
+	{"UTF-8", []byte{0xEF, 0xBB, 0xBF}},
+	{"UTF-16BE", []byte{0xFE, 0xFF}},
+	{"UTF-16LE", []byte{0xFF, 0xFE}},
+}
+
+// detectBOMEncoding reports the charset implied by data's byte order mark
+// and how many leading bytes it occupies, or ("", 0) if data has none.
+func detectBOMEncoding(data []byte) (string, int) {
+	for _, sig := range bomSignatures {
+		if bytes.HasPrefix(data, sig.bom) {
+			return sig.name, len(sig.bom)
+		}
+	}
+	return "", 0
+}
+
+// detectEncoding determines data's character encoding: first via BOM
+// sniffing, then (for data without a BOM that isn't valid UTF-8) via chardet's
+// statistical detector covering common single- and multi-byte encodings.
+// chardet never reports "ISO-8859-15" (it can't distinguish it from
+// "ISO-8859-1"/"windows-1252" statistically), so that encoding is only
+// reachable by a caller constructing a DetectedEncoding directly and calling
+// transcodeToUTF8 itself. It returns the detected encoding and the number of
+// leading BOM bytes to strip before transcoding.
+func detectEncoding(data []byte) (DetectedEncoding, int) {
+	if name, bomLen := detectBOMEncoding(data); name != "" {
+		return DetectedEncoding{Name: name, Confidence: 100}, bomLen
+	}
+
+	if utf8.Valid(data) {
+		return utf8Encoding, 0
+	}
+
+	result, err := chardet.NewTextDetector().DetectBest(data)
+	if err != nil || result == nil {
+		return DetectedEncoding{}, 0
+	}
+
+	return DetectedEncoding{Name: result.Charset, Confidence: result.Confidence}, 0
+}
+
+// encodingByName maps a detected charset name to its
+// golang.org/x/text/encoding implementation. It returns nil for "UTF-8" (no
+// transcoding needed) and for any name it doesn't recognize. The
+// "ISO-8859-15" case is kept for callers that know a file is in that
+// encoding some other way (e.g. a declared Content-Type), since detectEncoding's
+// statistical detector never reports it.
+func encodingByName(name string) encoding.Encoding {
+	switch name {
+	case "UTF-16LE":
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)
+	case "UTF-16BE":
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM)
+	case "UTF-32LE":
+		return utf32.UTF32(utf32.LittleEndian, utf32.IgnoreBOM)
+	case "UTF-32BE":
+		return utf32.UTF32(utf32.BigEndian, utf32.IgnoreBOM)
+	case "windows-1252", "Windows-1252":
+		return charmap.Windows1252
+	case "ISO-8859-1":
+		return charmap.ISO8859_1
+	case "ISO-8859-15":
+		return charmap.ISO8859_15
+	case "Shift_JIS":
+		return japanese.ShiftJIS
+	case "EUC-JP":
+		return japanese.EUCJP
+	case "GB-18030", "GB18030", "GB 18030":
+		return simplifiedchinese.GB18030
+	case "Big5":
+		return traditionalchinese.Big5
+	case "KOI8-R":
+		return charmap.KOI8R
+	}
+	return nil
+}
+
+// transcodeToUTF8 decodes payload from enc's encoding into a UTF-8 string.
+// Unrecognized encodings (including "UTF-8" itself) are passed through
+// unchanged.
+func transcodeToUTF8(payload []byte, enc DetectedEncoding) (string, error) {
+	codec := encodingByName(enc.Name)
+	if codec == nil {
+		return string(payload), nil
+	}
+
+	decoded, err := codec.NewDecoder().Bytes(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to transcode from %s: %v", enc.Name, err)
+	}
+
+	return string(decoded), nil
+}
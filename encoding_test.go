@@ -0,0 +1,111 @@
+package file_extractor
+
+import "testing"
+
+func TestDetectBOMEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		wantName string
+		wantLen  int
+	}{
+		{"utf8 bom", []byte{0xEF, 0xBB, 0xBF, 'h', 'i'}, "UTF-8", 3},
+		{"utf16le bom", []byte{0xFF, 0xFE, 'h', 0}, "UTF-16LE", 2},
+		{"utf16be bom", []byte{0xFE, 0xFF, 0, 'h'}, "UTF-16BE", 2},
+		{"utf32le bom takes priority over utf16le", []byte{0xFF, 0xFE, 0x00, 0x00, 'h', 0, 0, 0}, "UTF-32LE", 4},
+		{"utf32be bom", []byte{0x00, 0x00, 0xFE, 0xFF, 0, 0, 0, 'h'}, "UTF-32BE", 4},
+		{"no bom", []byte("hello"), "", 0},
+		{"empty", []byte{}, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, n := detectBOMEncoding(tt.data)
+			if name != tt.wantName || n != tt.wantLen {
+				t.Errorf("detectBOMEncoding(%v) = (%q, %d), want (%q, %d)", tt.data, name, n, tt.wantName, tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestDetectEncoding_UTF8NoBOM(t *testing.T) {
+	enc, bomLen := detectEncoding([]byte("plain ASCII/UTF-8 text"))
+	if enc.Name != "UTF-8" || enc.Confidence != 100 {
+		t.Errorf("expected confident UTF-8, got %+v", enc)
+	}
+	if bomLen != 0 {
+		t.Errorf("expected no BOM bytes to strip, got %d", bomLen)
+	}
+}
+
+func TestDetectEncoding_BOMTakesPriority(t *testing.T) {
+	data := []byte{0xFF, 0xFE, 'h', 0, 'i', 0}
+	enc, bomLen := detectEncoding(data)
+	if enc.Name != "UTF-16LE" || enc.Confidence != 100 {
+		t.Errorf("expected confident UTF-16LE, got %+v", enc)
+	}
+	if bomLen != 2 {
+		t.Errorf("expected 2 BOM bytes to strip, got %d", bomLen)
+	}
+}
+
+func TestDetectEncoding_LowConfidenceGarbage(t *testing.T) {
+	data := []byte{0xDE, 0xAD, 0xBE, 0xEF, 0xFF, 0xFD, 0xFC, 0x01, 0x02, 0x03}
+	enc, _ := detectEncoding(data)
+	if enc.Confidence >= minDetectionConfidence {
+		t.Errorf("expected low-confidence result for garbage bytes, got %+v", enc)
+	}
+}
+
+func TestEncodingByName(t *testing.T) {
+	tests := []struct {
+		name   string
+		wantOK bool
+	}{
+		{"UTF-16LE", true},
+		{"UTF-16BE", true},
+		{"UTF-32LE", true},
+		{"UTF-32BE", true},
+		{"windows-1252", true},
+		{"ISO-8859-1", true},
+		{"ISO-8859-15", true},
+		{"Shift_JIS", true},
+		{"GB-18030", true},
+		{"GB18030", true},
+		{"GB 18030", true},
+		{"Big5", true},
+		{"KOI8-R", true},
+		{"UTF-8", false},
+		{"not-a-real-encoding", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := encodingByName(tt.name) != nil; got != tt.wantOK {
+				t.Errorf("encodingByName(%q) present = %v, want %v", tt.name, got, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestTranscodeToUTF8_UTF16LE(t *testing.T) {
+	// "hi" encoded as UTF-16LE, BOM already stripped
+	data := []byte{'h', 0, 'i', 0}
+	text, err := transcodeToUTF8(data, DetectedEncoding{Name: "UTF-16LE", Confidence: 100})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "hi" {
+		t.Errorf("expected %q, got %q", "hi", text)
+	}
+}
+
+func TestTranscodeToUTF8_UnrecognizedPassesThrough(t *testing.T) {
+	text, err := transcodeToUTF8([]byte("already utf-8"), DetectedEncoding{Name: "UTF-8", Confidence: 100})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "already utf-8" {
+		t.Errorf("expected passthrough, got %q", text)
+	}
+}
@@ -2,9 +2,9 @@ package file_extractor
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -13,84 +13,53 @@ import (
 	"github.com/dslipak/pdf"
 )
 
+// ExtractionResult is the detailed outcome of ExtractTextDetailed, carrying
+// the character encoding that was detected (and, if non-UTF-8, transcoded
+// from) alongside the extracted text.
+type ExtractionResult struct {
+	Success  bool
+	Text     string
+	Encoding DetectedEncoding
+}
+
 // ExtractText extracts text content from a file if possible
 // Returns (success, text, error)
 // - success: true if text was successfully extracted
 // - text: the extracted text content (empty if success is false)
 // - error: any error that occurred during processing
 func ExtractText(filePath string) (bool, string, error) {
-	// Check if it's a PDF file
-	if strings.ToLower(filepath.Ext(filePath)) == ".pdf" {
-		return extractPDFText(filePath)
-	}
-
-	// Check if file is a supported text type
-	isText, _, err := isTextFile(filePath)
-	if err != nil {
-		return false, "", fmt.Errorf("failed to analyze file type: %v", err)
-	}
-
-	if !isText {
-		return false, "", nil // Not an error, just not a text file
-	}
-
-	// Read file content
-	data, err := os.ReadFile(filePath)
+	result, err := ExtractTextDetailed(filePath)
 	if err != nil {
-		return false, "", fmt.Errorf("failed to read file %s: %v", filePath, err)
+		return false, "", err
 	}
-
-	content := string(data)
-
-	// Validate that content is valid UTF-8 text
-	if !utf8.ValidString(content) {
-		return false, "", nil // Not valid UTF-8, can't extract as text
-	}
-
-	return true, content, nil
+	return result.Success, result.Text, nil
 }
 
-// isTextFile determines if a file is a text file using multiple detection methods
-func isTextFile(filePath string) (bool, string, error) {
-	// Method 1: Check by file extension first (fast)
-	if isTextByExtension(filePath) {
-		return true, "text/plain", nil
-	}
-
-	// Method 2: Use HTTP content detection with file sample
+// ExtractTextDetailed is ExtractText's richer counterpart: alongside the
+// extracted text, it reports the character encoding it detected the file to
+// be in (and, for plain-text files, transcoded from on the way to UTF-8).
+//
+// It opens filePath and delegates to ExtractReader, so the file is sniffed
+// and streamed rather than loaded into memory outright.
+func ExtractTextDetailed(filePath string) (*ExtractionResult, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
-		return false, "", fmt.Errorf("failed to open file: %v", err)
+		return nil, fmt.Errorf("failed to read file %s: %v", filePath, err)
 	}
 	defer file.Close()
 
-	// Read first 512 bytes for content type detection
-	buffer := make([]byte, 512)
-	n, err := file.Read(buffer)
-	if err != nil && err != io.EOF {
-		return false, "", fmt.Errorf("failed to read file sample: %v", err)
-	}
-
-	// Detect content type using HTTP package
-	contentType := http.DetectContentType(buffer[:n])
-	
-	// Method 3: Check if detected type is text-based
-	if isTextContentType(contentType) {
-		return true, contentType, nil
-	}
-
-	// Method 4: Binary heuristic - check if content is mostly printable UTF-8
-	if n > 0 && isLikelyTextContent(buffer[:n]) {
-		return true, "text/plain", nil
+	extraction, err := ExtractReader(context.Background(), file, Hint{FileName: filePath}, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	return false, contentType, nil
+	return &ExtractionResult{Success: extraction.Success, Text: extraction.Text, Encoding: extraction.Encoding}, nil
 }
 
 // isTextByExtension checks if file extension indicates text content
 func isTextByExtension(filePath string) bool {
 	ext := strings.ToLower(filepath.Ext(filePath))
-	
+
 	textExtensions := map[string]bool{
 		".txt":      true,
 		".md":       true,
@@ -132,7 +101,7 @@ func isTextByExtension(filePath string) bool {
 		".bib":      true,
 		"":          true, // files without extension might be text
 	}
-	
+
 	return textExtensions[ext]
 }
 
@@ -141,7 +110,7 @@ func isTextContentType(contentType string) bool {
 	// Split off charset if present
 	mainType := strings.Split(contentType, ";")[0]
 	mainType = strings.TrimSpace(strings.ToLower(mainType))
-	
+
 	textTypes := map[string]bool{
 		"text/plain":             true,
 		"text/html":              true,
@@ -161,12 +130,12 @@ func isTextContentType(contentType string) bool {
 		"application/yaml":       true,
 		"application/x-yaml":     true,
 	}
-	
+
 	// Also check if it starts with "text/"
 	if strings.HasPrefix(mainType, "text/") {
 		return true
 	}
-	
+
 	return textTypes[mainType]
 }
 
@@ -176,6 +145,13 @@ func isLikelyTextContent(data []byte) bool {
 		return true
 	}
 
+	// A UTF-16/UTF-32 BOM is a strong, specific signal on its own; those
+	// encodings routinely contain null bytes, so skip straight past the
+	// checks below rather than letting them reject it.
+	if bomName, _ := detectBOMEncoding(data); bomName != "" && bomName != "UTF-8" {
+		return true
+	}
+
 	// Check if content is valid UTF-8
 	if !utf8.Valid(data) {
 		return false
@@ -191,7 +167,7 @@ func isLikelyTextContent(data []byte) bool {
 	// Count printable vs non-printable characters
 	printableCount := 0
 	controlCount := 0
-	
+
 	for _, b := range data {
 		switch {
 		case b >= 32 && b <= 126: // ASCII printable
@@ -202,73 +178,96 @@ func isLikelyTextContent(data []byte) bool {
 			controlCount++
 		}
 	}
-	
+
 	// If more than 85% of characters are printable, consider it text
 	totalChars := len(data)
 	if totalChars == 0 {
 		return true
 	}
-	
+
 	printableRatio := float64(printableCount) / float64(totalChars)
 	return printableRatio > 0.85
 }
 
+// defaultMaxPDFPages limits how many pages extractPDFText reads, to prevent
+// hanging on huge PDFs.
+const defaultMaxPDFPages = 100
+
 // extractPDFText extracts text content from a PDF file
 func extractPDFText(filePath string) (bool, string, error) {
+	var textBuffer bytes.Buffer
+	wrote, err := writePDFPages(filePath, defaultMaxPDFPages, &textBuffer)
+	if err != nil {
+		return false, "", err
+	}
+	if !wrote {
+		return false, "", nil
+	}
+	return true, textBuffer.String(), nil
+}
+
+// writePDFPages reads up to maxPages (0 means no limit) of the PDF at
+// filePath and writes each page's text to out as it's read, separated by
+// newlines. It reports whether any page yielded non-whitespace text.
+func writePDFPages(filePath string, maxPages int, out io.Writer) (bool, error) {
+	return writePDFPagesWithHook(filePath, maxPages, out, nil)
+}
+
+// writePDFPagesWithHook is writePDFPages with an optional per-page hook that
+// can replace a page's extracted text before it's written out (e.g. an OCR
+// fallback for pages GetPlainText returns little or nothing for). A nil hook
+// behaves exactly like writePDFPages.
+func writePDFPagesWithHook(filePath string, maxPages int, out io.Writer, hook func(pageNum int, text string) string) (bool, error) {
 	// Open the PDF file
 	file, err := os.Open(filePath)
 	if err != nil {
-		return false, "", fmt.Errorf("failed to open PDF file: %v", err)
+		return false, fmt.Errorf("failed to open PDF file: %v", err)
 	}
 	defer file.Close()
 
 	// Get file info for size
 	fileInfo, err := file.Stat()
 	if err != nil {
-		return false, "", fmt.Errorf("failed to get PDF file info: %v", err)
+		return false, fmt.Errorf("failed to get PDF file info: %v", err)
 	}
 
 	// Read the PDF
 	reader, err := pdf.NewReader(file, fileInfo.Size())
 	if err != nil {
 		// If we can't read the PDF, treat it as a binary file (not text-extractable)
-		return false, "", nil
+		return false, nil
 	}
 
-	// Extract text from all pages
-	var textBuffer bytes.Buffer
 	numPages := reader.NumPage()
-	
-	// Limit pages to prevent hanging on large PDFs
-	maxPages := 100
-	if numPages > maxPages {
+	if maxPages > 0 && numPages > maxPages {
 		numPages = maxPages
 	}
-	
+
+	wroteText := false
 	for i := 1; i <= numPages; i++ {
 		page := reader.Page(i)
 		if page.V.IsNull() {
 			continue
 		}
-		
+
 		text, err := page.GetPlainText(nil)
 		if err != nil {
 			// Skip pages that can't be read
 			continue
 		}
-		
-		textBuffer.WriteString(text)
-		if i < numPages {
-			textBuffer.WriteString("\n")
+
+		if hook != nil {
+			text = hook(i, text)
 		}
-	}
 
-	extractedText := textBuffer.String()
-	
-	// If no text was extracted, return false
-	if len(strings.TrimSpace(extractedText)) == 0 {
-		return false, "", nil
+		io.WriteString(out, text)
+		if i < numPages {
+			io.WriteString(out, "\n")
+		}
+		if strings.TrimSpace(text) != "" {
+			wroteText = true
+		}
 	}
 
-	return true, extractedText, nil
-}
\ No newline at end of file
+	return wroteText, nil
+}
@@ -313,9 +313,11 @@ func TestIsLikelyTextContent(t *testing.T) {
 func TestExtractText_UTF8Validation(t *testing.T) {
 	tempDir := os.TempDir()
 
-	// Create a file with invalid UTF-8
+	// Create a file with invalid UTF-8 that also isn't a recognizable
+	// encoding (not a BOM prefix, and low-confidence under the statistical
+	// charset detector), so it's rejected rather than transcoded.
 	invalidUTF8File := filepath.Join(tempDir, "invalid_utf8.txt")
-	invalidData := []byte{0xFF, 0xFE, 0xFD} // Invalid UTF-8 sequence
+	invalidData := []byte{0xDE, 0xAD, 0xBE, 0xEF, 0xFF, 0xFD, 0xFC, 0x01, 0x02, 0x03}
 	err := os.WriteFile(invalidUTF8File, invalidData, 0644)
 	if err != nil {
 		t.Fatalf("Failed to create test file: %v", err)
@@ -0,0 +1,186 @@
+package file_extractor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"strings"
+	"unicode"
+
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/tiff"
+)
+
+// defaultMinCharsPerPage is the non-whitespace character threshold below
+// which a PDF page is considered image-based and, if a PDFRasterizer is
+// configured, routed through OCR.
+const defaultMinCharsPerPage = 10
+
+// imageOCRMIMETypes are the image formats ExtractTextWithOptions routes
+// through the configured OCRBackend rather than rejecting as binary.
+var imageOCRMIMETypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/tiff": true,
+	"image/bmp":  true,
+}
+
+// OCRBackend recognizes text depicted in an image, such as a rasterized PDF
+// page or a scanned photo. lang is a backend-specific language identifier
+// (e.g. Tesseract's "eng", or "eng+fra" for multiple languages).
+type OCRBackend interface {
+	Recognize(ctx context.Context, img image.Image, lang string) (string, error)
+}
+
+// NoOpOCRBackend is the default OCRBackend: it recognizes nothing, so OCR
+// stays opt-in until a caller supplies a real backend via Options.
+type NoOpOCRBackend struct{}
+
+// Recognize implements OCRBackend by always reporting no text found.
+func (NoOpOCRBackend) Recognize(ctx context.Context, img image.Image, lang string) (string, error) {
+	return "", nil
+}
+
+// PDFRasterizer renders a single page of the PDF at filePath (1-indexed) to
+// an image, so it can be handed to an OCRBackend. This package has no
+// built-in implementation; callers bring their own, backed by pdfium, mupdf,
+// or similar.
+type PDFRasterizer interface {
+	RasterizePage(filePath string, pageNum int) (image.Image, error)
+}
+
+// Options configures ExtractTextWithOptions' OCR fallback for image-based
+// PDF pages and image files.
+type Options struct {
+	// OCR recognizes text from rasterized PDF pages and, for the MIME types
+	// in imageOCRMIMETypes, whole image files. A nil OCR leaves
+	// ExtractTextWithOptions equivalent to ExtractText.
+	OCR OCRBackend
+	// Rasterizer renders PDF pages to images for OCR. A nil Rasterizer means
+	// image-based PDF pages are left untranscribed, same as ExtractText.
+	Rasterizer PDFRasterizer
+	// Languages are passed to the OCRBackend, joined with "+" (Tesseract's
+	// multi-language syntax). Defaults to []string{"eng"}.
+	Languages []string
+	// MinCharsPerPage is the non-whitespace character count below which a
+	// PDF page is rasterized and OCR'd instead of trusting GetPlainText's
+	// output. Defaults to defaultMinCharsPerPage.
+	MinCharsPerPage int
+}
+
+// ocrLanguageTag joins languages into the backend language identifier
+// Recognize expects, defaulting to English when none are given.
+func ocrLanguageTag(languages []string) string {
+	if len(languages) == 0 {
+		return "eng"
+	}
+	return strings.Join(languages, "+")
+}
+
+// ExtractTextWithOptions extracts text from the file at filePath like
+// ExtractText, but falls back to opts.OCR for image-based PDF pages (when
+// opts.Rasterizer is set) and for whole image files, rather than reporting
+// them as binary.
+func ExtractTextWithOptions(filePath string, opts Options) (bool, string, error) {
+	ocr := opts.OCR
+	if ocr == nil {
+		ocr = NoOpOCRBackend{}
+	}
+
+	mime, err := DetectMIME(filePath)
+	if err != nil {
+		return ExtractText(filePath)
+	}
+
+	if imageOCRMIMETypes[mime] {
+		return extractImageTextOCR(filePath, ocr, opts.Languages)
+	}
+
+	if mime == "application/pdf" {
+		minChars := opts.MinCharsPerPage
+		if minChars <= 0 {
+			minChars = defaultMinCharsPerPage
+		}
+		return extractPDFTextOCR(filePath, ocr, opts.Rasterizer, opts.Languages, minChars)
+	}
+
+	return ExtractText(filePath)
+}
+
+// extractImageTextOCR decodes the image file at filePath and hands it to ocr
+// for recognition. It reports no success (rather than an error) for files
+// that sniff as an OCR-eligible MIME type but don't actually decode as one.
+func extractImageTextOCR(filePath string, ocr OCRBackend, languages []string) (bool, string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to read file %s: %v", filePath, err)
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return false, "", nil
+	}
+
+	text, err := ocr.Recognize(context.Background(), img, ocrLanguageTag(languages))
+	if err != nil {
+		return false, "", fmt.Errorf("OCR failed for %s: %v", filePath, err)
+	}
+	if strings.TrimSpace(text) == "" {
+		return false, "", nil
+	}
+
+	return true, text, nil
+}
+
+// extractPDFTextOCR writes each PDF page's text like extractPDFText, except
+// a page whose extracted text has fewer than minChars non-whitespace
+// characters is rasterized and OCR'd when rasterizer is set, replacing the
+// page's text with the OCR result.
+func extractPDFTextOCR(filePath string, ocr OCRBackend, rasterizer PDFRasterizer, languages []string, minChars int) (bool, string, error) {
+	hook := buildPDFOCRHook(filePath, ocr, rasterizer, ocrLanguageTag(languages), minChars)
+
+	var textBuffer bytes.Buffer
+	wrote, err := writePDFPagesWithHook(filePath, defaultMaxPDFPages, &textBuffer, hook)
+	if err != nil {
+		return false, "", err
+	}
+	if !wrote {
+		return false, "", nil
+	}
+	return true, textBuffer.String(), nil
+}
+
+// buildPDFOCRHook returns a writePDFPagesWithHook hook that replaces a
+// page's text with an OCR'd rasterization of it whenever the page's
+// extracted text has fewer than minChars non-whitespace characters. A nil
+// rasterizer, a rasterization failure, or an empty OCR result all leave the
+// page's original text untouched.
+func buildPDFOCRHook(filePath string, ocr OCRBackend, rasterizer PDFRasterizer, lang string, minChars int) func(pageNum int, text string) string {
+	return func(pageNum int, text string) string {
+		nonSpace := 0
+		for _, r := range text {
+			if !unicode.IsSpace(r) {
+				nonSpace++
+			}
+		}
+		if rasterizer == nil || nonSpace >= minChars {
+			return text
+		}
+
+		img, err := rasterizer.RasterizePage(filePath, pageNum)
+		if err != nil {
+			return text
+		}
+
+		ocrText, err := ocr.Recognize(context.Background(), img, lang)
+		if err != nil || strings.TrimSpace(ocrText) == "" {
+			return text
+		}
+		return ocrText
+	}
+}
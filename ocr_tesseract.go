@@ -0,0 +1,62 @@
+//go:build tesseract
+
+package file_extractor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"os/exec"
+)
+
+// TesseractOCRBackend recognizes text by shelling out to the tesseract CLI.
+// It requires a tesseract binary on PATH (or at BinaryPath) and is only
+// compiled in with the "tesseract" build tag, so the default build doesn't
+// depend on an external program being installed.
+type TesseractOCRBackend struct {
+	// BinaryPath overrides the tesseract executable invoked. Empty uses
+	// "tesseract" from PATH.
+	BinaryPath string
+}
+
+// Recognize implements OCRBackend by encoding img as a temporary PNG and
+// running it through tesseract, returning its recognized text.
+func (b TesseractOCRBackend) Recognize(ctx context.Context, img image.Image, lang string) (string, error) {
+	binary := b.BinaryPath
+	if binary == "" {
+		binary = "tesseract"
+	}
+
+	tmp, err := os.CreateTemp("", "file-extractor-ocr-*.png")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp image file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := png.Encode(tmp, img); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to encode image for OCR: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to close temp image file: %v", err)
+	}
+
+	args := []string{tmp.Name(), "stdout"}
+	if lang != "" {
+		args = append(args, "-l", lang)
+	}
+
+	cmd := exec.CommandContext(ctx, binary, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("tesseract failed: %v: %s", err, stderr.String())
+	}
+
+	return stdout.String(), nil
+}
@@ -0,0 +1,144 @@
+package file_extractor
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNoOpOCRBackend_RecognizesNothing(t *testing.T) {
+	text, err := (NoOpOCRBackend{}).Recognize(context.Background(), image.NewRGBA(image.Rect(0, 0, 1, 1)), "eng")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "" {
+		t.Errorf("expected no recognized text, got %q", text)
+	}
+}
+
+// fakeOCRBackend records the language it was asked to recognize and always
+// returns a fixed string, so tests can verify OCR was (or wasn't) invoked
+// without depending on a real OCR engine.
+type fakeOCRBackend struct {
+	text     string
+	gotLang  string
+	gotCalls int
+}
+
+func (f *fakeOCRBackend) Recognize(ctx context.Context, img image.Image, lang string) (string, error) {
+	f.gotLang = lang
+	f.gotCalls++
+	return f.text, nil
+}
+
+func TestExtractTextWithOptions_ImageRoutesThroughOCR(t *testing.T) {
+	imgPath := filepath.Join(t.TempDir(), "scan.png")
+	writeTestPNG(t, imgPath)
+
+	ocr := &fakeOCRBackend{text: "scanned page contents"}
+	success, text, err := ExtractTextWithOptions(imgPath, Options{OCR: ocr, Languages: []string{"eng", "fra"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !success || text != "scanned page contents" {
+		t.Errorf("expected the OCR backend's text, got success=%v text=%q", success, text)
+	}
+	if ocr.gotCalls != 1 {
+		t.Errorf("expected OCR to be invoked once, got %d", ocr.gotCalls)
+	}
+	if ocr.gotLang != "eng+fra" {
+		t.Errorf("expected languages joined as %q, got %q", "eng+fra", ocr.gotLang)
+	}
+}
+
+func TestExtractTextWithOptions_ImageNoOCRConfigured(t *testing.T) {
+	imgPath := filepath.Join(t.TempDir(), "scan.png")
+	writeTestPNG(t, imgPath)
+
+	success, text, err := ExtractTextWithOptions(imgPath, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if success || text != "" {
+		t.Errorf("expected no text without a configured OCR backend, got success=%v text=%q", success, text)
+	}
+}
+
+func TestExtractTextWithOptions_PlainTextUnaffected(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(filePath, []byte("hello options"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	success, text, err := ExtractTextWithOptions(filePath, Options{OCR: &fakeOCRBackend{text: "should not be used"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !success || text != "hello options" {
+		t.Errorf("expected the plain text content, got success=%v text=%q", success, text)
+	}
+}
+
+// fakeRasterizer reports how many times it was asked to rasterize a page,
+// and returns a trivial image for RasterizePage so tests don't need a real
+// PDF rendering implementation.
+type fakeRasterizer struct {
+	calls []int
+}
+
+func (f *fakeRasterizer) RasterizePage(filePath string, pageNum int) (image.Image, error) {
+	f.calls = append(f.calls, pageNum)
+	return image.NewRGBA(image.Rect(0, 0, 1, 1)), nil
+}
+
+func TestBuildPDFOCRHook_FallsBackBelowThreshold(t *testing.T) {
+	rasterizer := &fakeRasterizer{}
+	ocr := &fakeOCRBackend{text: "recognized from image"}
+	hook := buildPDFOCRHook("irrelevant.pdf", ocr, rasterizer, "eng", defaultMinCharsPerPage)
+
+	got := hook(1, "a")
+	if got != "recognized from image" {
+		t.Errorf("expected the OCR result for a short page, got %q", got)
+	}
+	if len(rasterizer.calls) != 1 || rasterizer.calls[0] != 1 {
+		t.Errorf("expected page 1 to be rasterized once, got %v", rasterizer.calls)
+	}
+
+	got = hook(2, strings.Repeat("word ", 10))
+	if got != strings.Repeat("word ", 10) {
+		t.Errorf("expected a page with enough text to be left alone, got %q", got)
+	}
+	if len(rasterizer.calls) != 1 {
+		t.Errorf("expected no additional rasterization for a page with enough text, got %v", rasterizer.calls)
+	}
+}
+
+func TestBuildPDFOCRHook_NoRasterizerLeavesTextUnchanged(t *testing.T) {
+	hook := buildPDFOCRHook("irrelevant.pdf", &fakeOCRBackend{text: "unused"}, nil, "eng", defaultMinCharsPerPage)
+	if got := hook(1, "a"); got != "a" {
+		t.Errorf("expected the original text without a rasterizer, got %q", got)
+	}
+}
+
+func writeTestPNG(t *testing.T, path string) {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			img.SetGray(x, y, color.Gray{Y: uint8(x * y)})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode test PNG: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("failed to write test PNG: %v", err)
+	}
+}
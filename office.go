@@ -0,0 +1,533 @@
+package file_extractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MIME types for the Office Open XML / OpenDocument formats this package
+// can extract structured content from.
+const (
+	mimeDocx = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	mimePptx = "application/vnd.openxmlformats-officedocument.presentationml.presentation"
+	mimeXlsx = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	mimeOdt  = "application/vnd.oasis.opendocument.text"
+	mimeOds  = "application/vnd.oasis.opendocument.spreadsheet"
+	mimeOdp  = "application/vnd.oasis.opendocument.presentation"
+)
+
+// Sheet is a single spreadsheet sheet extracted by ExtractStructured, as
+// rows of cell values in document order.
+type Sheet struct {
+	Name string
+	Rows [][]string
+}
+
+// StructuredDocument is the structured result of ExtractStructured,
+// preserving the paragraph/slide/sheet boundaries that ExtractText's
+// flattened string would otherwise lose. At most one of Paragraphs, Slides,
+// or Sheets is populated, depending on MIME; none is for an empty document.
+type StructuredDocument struct {
+	MIME       string
+	Paragraphs []string // docx, odt: one entry per paragraph
+	Slides     []string // pptx, odp: one entry per slide
+	Sheets     []Sheet  // xlsx, ods: one entry per sheet
+}
+
+// classifyOfficeContainer inspects a zip archive's well-known entries to
+// tell an OOXML/ODF document apart from a plain zip file.
+func classifyOfficeContainer(r *zip.Reader) string {
+	switch {
+	case zipHasEntry(r, "word/document.xml"):
+		return mimeDocx
+	case zipHasEntry(r, "ppt/presentation.xml"):
+		return mimePptx
+	case zipHasEntry(r, "xl/workbook.xml"):
+		return mimeXlsx
+	}
+
+	if data, err := readZipEntry(r, "mimetype"); err == nil {
+		switch strings.TrimSpace(string(data)) {
+		case mimeOdt, mimeOds, mimeOdp:
+			return strings.TrimSpace(string(data))
+		}
+	}
+
+	return ""
+}
+
+// officeKind reports the Office/ODF MIME type of filePath, and whether it
+// was recognized at all.
+func officeKind(filePath string) (string, bool) {
+	r, closeZip, err := openZipReader(filePath)
+	if err != nil {
+		return "", false
+	}
+	defer closeZip()
+
+	kind := classifyOfficeContainer(r)
+	return kind, kind != ""
+}
+
+// ExtractStructured extracts an Office/ODF document's content while
+// preserving its paragraph/slide/sheet structure, for callers that need
+// more than ExtractText's flattened string.
+func ExtractStructured(filePath string) (*StructuredDocument, error) {
+	r, closeZip, err := openZipReader(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer closeZip()
+
+	kind := classifyOfficeContainer(r)
+
+	switch kind {
+	case mimeDocx:
+		data, err := readZipEntry(r, "word/document.xml")
+		if err != nil {
+			return nil, err
+		}
+		paragraphs, err := xmlRunParagraphs(data, "t", "p")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse docx document: %v", err)
+		}
+		return &StructuredDocument{MIME: kind, Paragraphs: paragraphs}, nil
+
+	case mimePptx:
+		slides, err := pptxSlides(r)
+		if err != nil {
+			return nil, err
+		}
+		return &StructuredDocument{MIME: kind, Slides: slides}, nil
+
+	case mimeXlsx:
+		sheets, err := xlsxSheets(r)
+		if err != nil {
+			return nil, err
+		}
+		return &StructuredDocument{MIME: kind, Sheets: sheets}, nil
+
+	case mimeOdt:
+		data, err := readZipEntry(r, "content.xml")
+		if err != nil {
+			return nil, err
+		}
+		paragraphs, err := xmlParagraphs(data, "p")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse odt document: %v", err)
+		}
+		return &StructuredDocument{MIME: kind, Paragraphs: paragraphs}, nil
+
+	case mimeOds:
+		data, err := readZipEntry(r, "content.xml")
+		if err != nil {
+			return nil, err
+		}
+		sheets, err := odsSheets(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ods document: %v", err)
+		}
+		return &StructuredDocument{MIME: kind, Sheets: sheets}, nil
+
+	case mimeOdp:
+		data, err := readZipEntry(r, "content.xml")
+		if err != nil {
+			return nil, err
+		}
+		slides, err := odpSlides(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse odp document: %v", err)
+		}
+		return &StructuredDocument{MIME: kind, Slides: slides}, nil
+	}
+
+	return nil, fmt.Errorf("%s is not a recognized Office/ODF document", filePath)
+}
+
+// extractOfficeText flattens an Office/ODF document's structured content
+// into ExtractText's plain (success, text, error) shape.
+func extractOfficeText(filePath string) (bool, string, error) {
+	doc, err := ExtractStructured(filePath)
+	if err != nil {
+		return false, "", err
+	}
+
+	var out strings.Builder
+	switch {
+	case len(doc.Paragraphs) > 0:
+		out.WriteString(strings.Join(doc.Paragraphs, "\n"))
+	case len(doc.Slides) > 0:
+		for i, slide := range doc.Slides {
+			if i > 0 {
+				out.WriteString("\n--- Slide ---\n")
+			}
+			out.WriteString(slide)
+		}
+	case len(doc.Sheets) > 0:
+		for i, sheet := range doc.Sheets {
+			if i > 0 {
+				out.WriteString("\n")
+			}
+			for _, row := range sheet.Rows {
+				out.WriteString(strings.Join(row, "\t"))
+				out.WriteString("\n")
+			}
+		}
+	}
+
+	text := out.String()
+	if len(strings.TrimSpace(text)) == 0 {
+		return false, "", nil
+	}
+	return true, text, nil
+}
+
+// pptxSlides extracts one text block per slide from ppt/slides/slideN.xml,
+// in slide order.
+func pptxSlides(r *zip.Reader) ([]string, error) {
+	slideFiles := zipEntriesWithPrefix(r, "ppt/slides/slide")
+
+	slides := make([]string, 0, len(slideFiles))
+	for _, name := range slideFiles {
+		data, err := readZipEntry(r, name)
+		if err != nil {
+			return nil, err
+		}
+		paragraphs, err := xmlRunParagraphs(data, "t", "p")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", name, err)
+		}
+		slides = append(slides, strings.Join(paragraphs, "\n"))
+	}
+
+	return slides, nil
+}
+
+// xlsxSheets joins xl/sharedStrings.xml with each xl/worksheets/sheetN.xml
+// into one Sheet of TSV-style rows per worksheet.
+func xlsxSheets(r *zip.Reader) ([]Sheet, error) {
+	var sharedStrings []string
+	if zipHasEntry(r, "xl/sharedStrings.xml") {
+		data, err := readZipEntry(r, "xl/sharedStrings.xml")
+		if err != nil {
+			return nil, err
+		}
+		sharedStrings, err = xmlRunParagraphs(data, "t", "si")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse shared strings: %v", err)
+		}
+	}
+
+	sheetFiles := zipEntriesWithPrefix(r, "xl/worksheets/sheet")
+
+	sheets := make([]Sheet, 0, len(sheetFiles))
+	for i, name := range sheetFiles {
+		data, err := readZipEntry(r, name)
+		if err != nil {
+			return nil, err
+		}
+		rows, err := xlsxSheetRows(data, sharedStrings)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", name, err)
+		}
+		sheets = append(sheets, Sheet{Name: fmt.Sprintf("Sheet%d", i+1), Rows: rows})
+	}
+
+	return sheets, nil
+}
+
+// xlsxSheetRows decodes a worksheet's <row>/<c>/<v> cells into rows of cell
+// values, resolving shared-string cells (t="s") against sharedStrings. Cells
+// omitted from the XML for being empty are padded back in using each <c>'s
+// "r" column reference (e.g. "C1"), so columns stay aligned even when a row
+// has gaps.
+func xlsxSheetRows(data []byte, sharedStrings []string) ([][]string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var rows [][]string
+	var cellType string
+	var cellCol int
+	var cellValue strings.Builder
+	inValue := false
+
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch el := tok.(type) {
+		case xml.StartElement:
+			switch el.Name.Local {
+			case "row":
+				rows = append(rows, nil)
+			case "c":
+				cellType = xmlAttr(el, "t")
+				cellCol = columnIndexFromRef(xmlAttr(el, "r"))
+				cellValue.Reset()
+			case "v":
+				inValue = true
+			}
+		case xml.CharData:
+			if inValue {
+				cellValue.Write(el)
+			}
+		case xml.EndElement:
+			switch el.Name.Local {
+			case "v":
+				inValue = false
+			case "c":
+				value := cellValue.String()
+				if cellType == "s" {
+					if idx, err := strconv.Atoi(value); err == nil && idx >= 0 && idx < len(sharedStrings) {
+						value = sharedStrings[idx]
+					}
+				}
+				if len(rows) > 0 {
+					row := rows[len(rows)-1]
+					for cellCol >= 0 && len(row) < cellCol {
+						row = append(row, "")
+					}
+					rows[len(rows)-1] = append(row, value)
+				}
+			}
+		}
+	}
+
+	return rows, nil
+}
+
+// columnIndexFromRef returns the 0-based column index encoded in a cell
+// reference like "C1" or "AA17", or -1 if ref has no parseable column
+// letters (e.g. it's empty, as can happen with hand-rolled XLSX writers).
+func columnIndexFromRef(ref string) int {
+	col := 0
+	seen := false
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		seen = true
+		col = col*26 + int(r-'A'+1)
+	}
+	if !seen {
+		return -1
+	}
+	return col - 1
+}
+
+// odsSheets decodes an ODF spreadsheet's content.xml table:table elements
+// into rows of cell text.
+func odsSheets(data []byte) ([]Sheet, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var sheets []Sheet
+	var cellText strings.Builder
+	inParagraph := false
+
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch el := tok.(type) {
+		case xml.StartElement:
+			switch el.Name.Local {
+			case "table":
+				sheets = append(sheets, Sheet{Name: xmlAttr(el, "name")})
+			case "table-row":
+				if len(sheets) > 0 {
+					sheets[len(sheets)-1].Rows = append(sheets[len(sheets)-1].Rows, nil)
+				}
+			case "table-cell":
+				cellText.Reset()
+			case "p":
+				inParagraph = true
+			}
+		case xml.CharData:
+			if inParagraph {
+				cellText.Write(el)
+			}
+		case xml.EndElement:
+			switch el.Name.Local {
+			case "p":
+				inParagraph = false
+			case "table-cell":
+				if len(sheets) == 0 {
+					continue
+				}
+				rows := sheets[len(sheets)-1].Rows
+				if len(rows) > 0 {
+					rows[len(rows)-1] = append(rows[len(rows)-1], cellText.String())
+				}
+			}
+		}
+	}
+
+	return sheets, nil
+}
+
+// odpSlides decodes an ODF presentation's content.xml draw:page elements
+// into one text block per slide.
+func odpSlides(data []byte) ([]string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var slides []string
+	var current strings.Builder
+	var paragraphs []string
+	inParagraph := false
+	started := false
+
+	flush := func() {
+		if started {
+			slides = append(slides, strings.Join(paragraphs, "\n"))
+		}
+		paragraphs = nil
+	}
+
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch el := tok.(type) {
+		case xml.StartElement:
+			switch el.Name.Local {
+			case "page":
+				flush()
+				started = true
+			case "p":
+				inParagraph = true
+				current.Reset()
+			}
+		case xml.CharData:
+			if inParagraph {
+				current.Write(el)
+			}
+		case xml.EndElement:
+			if el.Name.Local == "p" {
+				inParagraph = false
+				paragraphs = append(paragraphs, current.String())
+			}
+		}
+	}
+	flush()
+
+	return slides, nil
+}
+
+// xmlRunParagraphs decodes data and groups run-level text (runElem, e.g.
+// docx/pptx's "t") into paragraphs delimited by paragraphElem (e.g. "p" or
+// "si"), returning one string per paragraph in document order.
+func xmlRunParagraphs(data []byte, runElem, paragraphElem string) ([]string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var paragraphs []string
+	var current strings.Builder
+	inRun := false
+
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch el := tok.(type) {
+		case xml.StartElement:
+			if el.Name.Local == runElem {
+				inRun = true
+			}
+		case xml.CharData:
+			if inRun {
+				current.Write(el)
+			}
+		case xml.EndElement:
+			switch el.Name.Local {
+			case runElem:
+				inRun = false
+			case paragraphElem:
+				paragraphs = append(paragraphs, current.String())
+				current.Reset()
+			}
+		}
+	}
+
+	// Trailing text not closed by a paragraph/si end (seen in some shared
+	// string tables) is kept rather than dropped.
+	if current.Len() > 0 {
+		paragraphs = append(paragraphs, current.String())
+	}
+
+	return paragraphs, nil
+}
+
+// xmlParagraphs decodes data and returns the text content of each top-level
+// paragraphElem element (e.g. ODF's text:p), including any nested runs.
+func xmlParagraphs(data []byte, paragraphElem string) ([]string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var paragraphs []string
+	var current strings.Builder
+	depth := 0
+
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch el := tok.(type) {
+		case xml.StartElement:
+			if el.Name.Local == paragraphElem {
+				depth++
+			}
+		case xml.CharData:
+			if depth > 0 {
+				current.Write(el)
+			}
+		case xml.EndElement:
+			if el.Name.Local == paragraphElem {
+				depth--
+				if depth == 0 {
+					paragraphs = append(paragraphs, current.String())
+					current.Reset()
+				}
+			}
+		}
+	}
+
+	return paragraphs, nil
+}
+
+// xmlAttr returns the value of the named attribute on el, ignoring its
+// namespace prefix, or "" if absent.
+func xmlAttr(el xml.StartElement, local string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,174 @@
+package file_extractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestXmlRunParagraphs(t *testing.T) {
+	docXML := `<w:document xmlns:w="ns">
+		<w:body>
+			<w:p><w:r><w:t>Hello </w:t></w:r><w:r><w:t>world</w:t></w:r></w:p>
+			<w:p><w:r><w:t>Second paragraph</w:t></w:r></w:p>
+		</w:body>
+	</w:document>`
+
+	got, err := xmlRunParagraphs([]byte(docXML), "t", "p")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"Hello world", "Second paragraph"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestXmlParagraphs(t *testing.T) {
+	contentXML := `<office:document-content xmlns:text="ns">
+		<text:p>First <text:span>paragraph</text:span></text:p>
+		<text:p>Second paragraph</text:p>
+	</office:document-content>`
+
+	got, err := xmlParagraphs([]byte(contentXML), "p")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"First paragraph", "Second paragraph"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestXlsxSheetRows(t *testing.T) {
+	sheetXML := `<worksheet xmlns="ns">
+		<sheetData>
+			<row><c r="A1" t="s"><v>0</v></c><c r="B1"><v>42</v></c></row>
+			<row><c r="A2" t="s"><v>1</v></c><c r="B2"><v>7</v></c></row>
+		</sheetData>
+	</worksheet>`
+
+	sharedStrings := []string{"Name", "Age"}
+	got, err := xlsxSheetRows([]byte(sheetXML), sharedStrings)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := [][]string{{"Name", "42"}, {"Age", "7"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestXlsxSheetRows_SkippedEmptyCellsStayAligned(t *testing.T) {
+	// B1 is empty and omitted entirely, as real XLSX writers do.
+	sheetXML := `<worksheet xmlns="ns">
+		<sheetData>
+			<row><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>
+		</sheetData>
+	</worksheet>`
+
+	sharedStrings := []string{"first", "third"}
+	got, err := xlsxSheetRows([]byte(sheetXML), sharedStrings)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := [][]string{{"first", "", "third"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestOdsSheets(t *testing.T) {
+	contentXML := `<office:document-content xmlns:table="ns" xmlns:text="ns">
+		<table:table table:name="Sheet1">
+			<table:table-row>
+				<table:table-cell><text:p>Name</text:p></table:table-cell>
+				<table:table-cell><text:p>Age</text:p></table:table-cell>
+			</table:table-row>
+		</table:table>
+	</office:document-content>`
+
+	got, err := odsSheets([]byte(contentXML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Sheet{{Name: "Sheet1", Rows: [][]string{{"Name", "Age"}}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestOdpSlides(t *testing.T) {
+	contentXML := `<office:document-content xmlns:draw="ns" xmlns:text="ns">
+		<draw:page><text:p>Title slide</text:p></draw:page>
+		<draw:page><text:p>Second slide</text:p></draw:page>
+	</office:document-content>`
+
+	got, err := odpSlides([]byte(contentXML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"Title slide", "Second slide"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// buildTestZip writes an in-memory zip archive with the given name->content
+// entries, for exercising the zip-backed classification/extraction helpers
+// without needing real docx/odt fixture files on disk.
+func buildTestZip(t *testing.T, entries map[string]string) *zip.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range entries {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("failed to create zip entry %s: %v", name, err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatalf("failed to write zip entry %s: %v", name, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close zip writer: %v", err)
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("failed to reopen zip: %v", err)
+	}
+	return r
+}
+
+func TestClassifyOfficeContainer(t *testing.T) {
+	tests := []struct {
+		name     string
+		entries  map[string]string
+		expected string
+	}{
+		{"docx", map[string]string{"word/document.xml": "<doc/>"}, mimeDocx},
+		{"pptx", map[string]string{"ppt/presentation.xml": "<pres/>"}, mimePptx},
+		{"xlsx", map[string]string{"xl/workbook.xml": "<wb/>"}, mimeXlsx},
+		{"odt", map[string]string{"mimetype": mimeOdt}, mimeOdt},
+		{"plain zip", map[string]string{"readme.txt": "just a zip"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := buildTestZip(t, tt.entries)
+			got := classifyOfficeContainer(r)
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package file_extractor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/text/transform"
+)
+
+// Hint supplies optional context ExtractReader and ExtractStream can use
+// alongside their content sniff, for formats content sniffing alone can't
+// disambiguate (e.g. a plain-text file with no distinctive magic bytes).
+type Hint struct {
+	FileName string
+}
+
+// Limits bounds the work ExtractReader and ExtractStream do, so callers can
+// cap memory and time spent on arbitrarily large or adversarial input. Pass
+// nil to either function to use DefaultLimits.
+type Limits struct {
+	MaxBytes          int64         // bytes read from a plain-text stream, or spooled from a PDF/archive source
+	MaxPages          int           // PDF pages read
+	MaxArchiveEntries int           // archive entries, in walk order, considered for output; skipped entries count too
+	Timeout           time.Duration // deadline checked once, after the content sniff; 0 means no timeout. Work already under way is not interrupted
+}
+
+// DefaultLimits are the limits ExtractReader and ExtractStream use when nil
+// is passed in their place.
+var DefaultLimits = Limits{
+	MaxBytes:          100 << 20, // 100MB
+	MaxPages:          100,
+	MaxArchiveEntries: 10000,
+}
+
+// Extraction is the outcome of ExtractReader: the same (success, text,
+// encoding) information as ExtractionResult, but for content read from an
+// arbitrary io.Reader rather than a file path.
+type Extraction struct {
+	Success  bool
+	Text     string
+	MIME     string
+	Encoding DetectedEncoding
+}
+
+// effectiveLimits fills in DefaultLimits for any zero-valued field of
+// limits, or returns DefaultLimits outright if limits is nil.
+func effectiveLimits(limits *Limits) Limits {
+	if limits == nil {
+		return DefaultLimits
+	}
+	effective := *limits
+	if effective.MaxBytes == 0 {
+		effective.MaxBytes = DefaultLimits.MaxBytes
+	}
+	if effective.MaxPages == 0 {
+		effective.MaxPages = DefaultLimits.MaxPages
+	}
+	if effective.MaxArchiveEntries == 0 {
+		effective.MaxArchiveEntries = DefaultLimits.MaxArchiveEntries
+	}
+	return effective
+}
+
+// ExtractReader extracts text from r, sniffing its content type from a
+// bufio.Reader.Peek rather than consuming it up front, and returns the full
+// result in memory. For bounded-memory extraction of large plain-text
+// streams, use ExtractStream instead.
+func ExtractReader(ctx context.Context, r io.Reader, hint Hint, limits *Limits) (*Extraction, error) {
+	var buf bytes.Buffer
+	mime, enc, success, err := extractTo(ctx, r, hint, effectiveLimits(limits), &buf)
+	if err != nil {
+		return nil, err
+	}
+	text := ""
+	if success {
+		text = buf.String()
+	}
+	return &Extraction{Success: success, Text: text, MIME: mime, Encoding: enc}, nil
+}
+
+// ExtractStream extracts text from r and writes it to out: in fixed-size
+// chunks (not split on line boundaries) for plain text, page-by-page for
+// PDF, and member-by-member for archives, once the whole archive has been
+// walked. Pass nil for limits to use DefaultLimits.
+func ExtractStream(ctx context.Context, r io.Reader, hint Hint, out io.Writer, limits *Limits) error {
+	_, _, _, err := extractTo(ctx, r, hint, effectiveLimits(limits), out)
+	return err
+}
+
+// extractTo sniffs r's content (via Peek, so nothing is consumed that the
+// dispatched extractor still needs) and streams its text to out, returning
+// the sniffed MIME type, detected encoding, and whether any text was
+// extracted.
+func extractTo(ctx context.Context, r io.Reader, hint Hint, limits Limits, out io.Writer) (string, DetectedEncoding, bool, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if limits.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, limits.Timeout)
+		defer cancel()
+	}
+
+	br := bufio.NewReaderSize(r, sniffSampleSize)
+	sample, _ := br.Peek(sniffSampleSize)
+	mime := activeDetector.Detect(sample)
+
+	if err := ctx.Err(); err != nil {
+		return mime, DetectedEncoding{}, false, err
+	}
+
+	switch {
+	case mime == "application/pdf":
+		success, err := extractPDFReaderTo(br, limits.MaxBytes, limits.MaxPages, out)
+		return mime, utf8Encoding, success, err
+
+	case mime == "application/zip":
+		tmpPath, cleanup, err := spoolLimited(br, limits.MaxBytes)
+		if err != nil {
+			return mime, DetectedEncoding{}, false, err
+		}
+		defer cleanup()
+
+		if _, ok := officeKind(tmpPath); ok {
+			success, text, err := extractOfficeText(tmpPath)
+			if err != nil {
+				return mime, DetectedEncoding{}, false, err
+			}
+			if success {
+				io.WriteString(out, text)
+			}
+			return mime, utf8Encoding, success, nil
+		}
+
+		success, err := extractArchiveReaderTo(tmpPath, limits, out)
+		return mime, utf8Encoding, success, err
+
+	case archiveMIMETypes[mime]:
+		tmpPath, cleanup, err := spoolLimited(br, limits.MaxBytes)
+		if err != nil {
+			return mime, DetectedEncoding{}, false, err
+		}
+		defer cleanup()
+
+		success, err := extractArchiveReaderTo(tmpPath, limits, out)
+		return mime, utf8Encoding, success, err
+
+	default:
+		return extractPlainTextReaderTo(br, sample, hint, mime, limits, out)
+	}
+}
+
+// extractPlainTextReaderTo handles the plain-text dispatch path: it detects
+// the encoding from the already-peeked sample, then streams the rest of br
+// through a transcoding reader straight to out, bounded by limits.MaxBytes,
+// without ever buffering the whole input itself.
+func extractPlainTextReaderTo(br *bufio.Reader, sample []byte, hint Hint, mime string, limits Limits, out io.Writer) (string, DetectedEncoding, bool, error) {
+	extByHint := hint.FileName != "" && isTextByExtension(hint.FileName)
+
+	if extByHint {
+		// Cross-check the extension against the sniffed content so a
+		// renamed PNG or archive isn't happily streamed out as text.
+		if err := textExtensionMismatch(hint.FileName, mime); err != nil {
+			return mime, DetectedEncoding{}, false, err
+		}
+	} else if !isTextContentType(mime) && !isLikelyTextContent(sample) {
+		return mime, DetectedEncoding{}, false, nil
+	}
+
+	enc, bomLen := detectEncoding(sample)
+	if enc.Name == "" || (enc.Name != "UTF-8" && enc.Confidence < minDetectionConfidence) {
+		return mime, enc, false, nil
+	}
+
+	if _, err := br.Discard(bomLen); err != nil {
+		return mime, enc, false, fmt.Errorf("failed to skip byte order mark: %v", err)
+	}
+
+	codec := encodingByName(enc.Name)
+	if codec == nil && enc.Name != "UTF-8" {
+		// No transcoder for this encoding: treat it as undetected rather
+		// than passing non-UTF-8 bytes through as text.
+		return mime, enc, false, nil
+	}
+
+	var source io.Reader = io.LimitReader(br, limits.MaxBytes)
+	if codec != nil {
+		source = transform.NewReader(source, codec.NewDecoder())
+	}
+
+	n, err := io.Copy(out, source)
+	if err != nil {
+		return mime, enc, false, fmt.Errorf("failed to stream text content: %v", err)
+	}
+
+	return mime, enc, n > 0 || len(sample) == 0, nil
+}
+
+// extractPDFReaderTo spools br to a temp file (the PDF library needs random
+// access), bounded by maxBytes, and writes each page's text to out as it's
+// read, up to maxPages.
+func extractPDFReaderTo(br *bufio.Reader, maxBytes int64, maxPages int, out io.Writer) (bool, error) {
+	tmpPath, cleanup, err := spoolLimited(br, maxBytes)
+	if err != nil {
+		return false, err
+	}
+	defer cleanup()
+
+	return writePDFPages(tmpPath, maxPages, out)
+}
+
+// extractArchiveReaderTo walks the whole archive at tmpPath, then writes
+// each member's extracted text to out, stopping after the first
+// limits.MaxArchiveEntries entries.
+func extractArchiveReaderTo(tmpPath string, limits Limits, out io.Writer) (bool, error) {
+	archiveLimits := DefaultArchiveLimits
+	result, err := ExtractArchive(tmpPath, &archiveLimits)
+	if err != nil {
+		return false, err
+	}
+
+	wrote := false
+	for i, entry := range result.Entries {
+		if i >= limits.MaxArchiveEntries {
+			break
+		}
+		if entry.Err != nil || strings.TrimSpace(entry.Text) == "" {
+			continue
+		}
+		fmt.Fprintf(out, "=== %s ===\n", entry.Path)
+		io.WriteString(out, entry.Text)
+		io.WriteString(out, "\n")
+		wrote = true
+	}
+
+	return wrote, nil
+}
+
+// spoolLimited copies r to a temp file, bounded by maxBytes, for formats
+// (PDF, zip, tar, 7z) whose readers need random access that an arbitrary
+// io.Reader can't provide. The caller is responsible for invoking the
+// returned cleanup function once done with the temp file.
+func spoolLimited(r io.Reader, maxBytes int64) (path string, cleanup func(), err error) {
+	tmp, err := os.CreateTemp("", "file-extractor-stream-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temp file: %v", err)
+	}
+	defer tmp.Close()
+
+	n, err := io.Copy(tmp, io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", nil, fmt.Errorf("failed to read stream: %v", err)
+	}
+	if n > maxBytes {
+		os.Remove(tmp.Name())
+		return "", nil, fmt.Errorf("stream exceeds size limit (%d bytes)", maxBytes)
+	}
+
+	return tmp.Name(), func() { os.Remove(tmp.Name()) }, nil
+}
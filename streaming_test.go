@@ -0,0 +1,123 @@
+package file_extractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExtractReader_PlainText(t *testing.T) {
+	extraction, err := ExtractReader(context.Background(), strings.NewReader("hello streamed world"), Hint{FileName: "notes.txt"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !extraction.Success || extraction.Text != "hello streamed world" {
+		t.Errorf("expected successful extraction of the plain text, got %+v", extraction)
+	}
+}
+
+func TestExtractReader_NoHintRejectsBinary(t *testing.T) {
+	pngHeader := []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A, 0x00, 0x00}
+	extraction, err := ExtractReader(context.Background(), bytes.NewReader(pngHeader), Hint{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if extraction.Success {
+		t.Errorf("expected a PNG header to not be extracted as text, got %+v", extraction)
+	}
+}
+
+func TestExtractReader_ExtensionContentMismatch(t *testing.T) {
+	pngHeader := []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A, 0x00, 0x00}
+	_, err := ExtractReader(context.Background(), bytes.NewReader(pngHeader), Hint{FileName: "photo.txt"}, nil)
+	if err == nil {
+		t.Fatal("expected a mismatch error for a .txt hint wrapping PNG content")
+	}
+	var mismatch *MismatchError
+	if !errors.As(err, &mismatch) {
+		t.Errorf("expected a *MismatchError, got %T: %v", err, err)
+	}
+}
+
+func TestExtractReader_UTF16LEStream(t *testing.T) {
+	data := append([]byte{0xFF, 0xFE}, encodeUTF16LE("hi there")...)
+	extraction, err := ExtractReader(context.Background(), bytes.NewReader(data), Hint{FileName: "notes.txt"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !extraction.Success || extraction.Text != "hi there" {
+		t.Errorf("expected transcoded %q, got %+v", "hi there", extraction)
+	}
+	if extraction.Encoding.Name != "UTF-16LE" {
+		t.Errorf("expected detected encoding UTF-16LE, got %+v", extraction.Encoding)
+	}
+}
+
+func TestExtractStream_WritesToWriter(t *testing.T) {
+	var out bytes.Buffer
+	err := ExtractStream(context.Background(), strings.NewReader("streamed to a writer"), Hint{FileName: "notes.txt"}, &out, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "streamed to a writer" {
+		t.Errorf("expected the writer to contain the source text, got %q", out.String())
+	}
+}
+
+func TestExtractReader_MaxBytesLimit(t *testing.T) {
+	limits := &Limits{MaxBytes: 5}
+	_, err := ExtractReader(context.Background(), strings.NewReader("this is much longer than five bytes"), Hint{FileName: "notes.txt"}, limits)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestExtractReader_Zip(t *testing.T) {
+	var zipBuf bytes.Buffer
+	w := zip.NewWriter(&zipBuf)
+	entry, err := w.Create("notes.txt")
+	if err != nil {
+		t.Fatalf("failed to create zip entry: %v", err)
+	}
+	if _, err := entry.Write([]byte("zipped via a reader")); err != nil {
+		t.Fatalf("failed to write zip entry: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close zip writer: %v", err)
+	}
+
+	extraction, err := ExtractReader(context.Background(), bytes.NewReader(zipBuf.Bytes()), Hint{FileName: "archive.zip"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !extraction.Success || !strings.Contains(extraction.Text, "zipped via a reader") {
+		t.Errorf("expected the zip member's text to be extracted, got %+v", extraction)
+	}
+}
+
+func TestEffectiveLimits_FillsZeroFields(t *testing.T) {
+	got := effectiveLimits(&Limits{MaxPages: 7})
+	if got.MaxPages != 7 {
+		t.Errorf("expected the explicit MaxPages to be kept, got %d", got.MaxPages)
+	}
+	if got.MaxBytes != DefaultLimits.MaxBytes {
+		t.Errorf("expected MaxBytes to fall back to the default, got %d", got.MaxBytes)
+	}
+	if got.MaxArchiveEntries != DefaultLimits.MaxArchiveEntries {
+		t.Errorf("expected MaxArchiveEntries to fall back to the default, got %d", got.MaxArchiveEntries)
+	}
+}
+
+// encodeUTF16LE is a minimal little-endian UTF-16 encoder for ASCII test
+// fixtures, avoiding a dependency on the package's own transcoder in tests
+// that exercise it.
+func encodeUTF16LE(s string) []byte {
+	out := make([]byte, 0, len(s)*2)
+	for _, r := range s {
+		out = append(out, byte(r), byte(r>>8))
+	}
+	return out
+}
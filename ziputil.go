@@ -0,0 +1,93 @@
+package file_extractor
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// openZipReader opens filePath as a zip archive, returning the reader
+// alongside a closer that must be called once the caller is done reading
+// from it. Shared by the Office/ODF extractors and the archive walker.
+func openZipReader(filePath string) (*zip.Reader, func() error, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open file %s: %v", filePath, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("failed to stat file %s: %v", filePath, err)
+	}
+
+	r, err := zip.NewReader(file, info.Size())
+	if err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("failed to read zip archive %s: %v", filePath, err)
+	}
+
+	return r, file.Close, nil
+}
+
+// zipHasEntry reports whether name is present in the archive.
+func zipHasEntry(r *zip.Reader, name string) bool {
+	for _, f := range r.File {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// readZipEntry reads and returns the full contents of the named entry.
+func readZipEntry(r *zip.Reader, name string) ([]byte, error) {
+	for _, f := range r.File {
+		if f.Name == name {
+			rc, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("failed to open zip entry %s: %v", name, err)
+			}
+			defer rc.Close()
+			return io.ReadAll(rc)
+		}
+	}
+	return nil, fmt.Errorf("zip entry %q not found", name)
+}
+
+// zipEntriesWithPrefix returns the names of entries under prefix, sorted by
+// the trailing number in their filename (sheet1.xml, sheet2.xml, ...,
+// sheet10.xml) rather than lexicographically.
+func zipEntriesWithPrefix(r *zip.Reader, prefix string) []string {
+	var names []string
+	for _, f := range r.File {
+		if strings.HasPrefix(f.Name, prefix) {
+			names = append(names, f.Name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return trailingNumber(names[i]) < trailingNumber(names[j])
+	})
+	return names
+}
+
+// trailingNumber extracts the run of digits immediately before the file
+// extension (e.g. "ppt/slides/slide12.xml" -> 12), defaulting to 0 if none
+// is found.
+func trailingNumber(name string) int {
+	base := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	i := len(base)
+	for i > 0 && base[i-1] >= '0' && base[i-1] <= '9' {
+		i--
+	}
+	n, err := strconv.Atoi(base[i:])
+	if err != nil {
+		return 0
+	}
+	return n
+}